@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package bulkop provides a reusable helper for running a large number of independent operations
+// (e.g. one Azure API call per item in a bulk resource) concurrently, with bounded concurrency,
+// retry of transient errors, cancellation of in-flight work on the first fatal error, and a
+// report of every item that ultimately failed.
+package bulkop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultMaxConcurrency is used when Config.MaxConcurrency is left unset.
+const defaultMaxConcurrency = 100
+
+// defaultMaxAttempts is used when Config.MaxAttempts is left unset - i.e. no retries.
+const defaultMaxAttempts = 1
+
+// Config controls how Run schedules and retries work.
+type Config struct {
+	// MaxConcurrency bounds how many items are in flight at once. Defaults to 100.
+	MaxConcurrency int
+
+	// MaxAttempts bounds how many times a single item is attempted, including the first try,
+	// before it is given up on and reported as a failure. Defaults to 1 (no retries).
+	MaxAttempts int
+}
+
+// RetryableError should be returned by the Run callback to mark an error as transient (e.g. a
+// 429 or 5xx Azure response) so the item is retried rather than immediately failing the batch.
+type RetryableError struct {
+	// Err is the underlying error.
+	Err error
+
+	// RetryAfter, if non-zero, is used as the wait before the next attempt instead of the
+	// default exponential backoff - set this from an Azure `Retry-After` response header.
+	RetryAfter time.Duration
+}
+
+func (e RetryableError) Error() string { return e.Err.Error() }
+
+func (e RetryableError) Unwrap() error { return e.Err }
+
+// Run executes fn once per item in items, running up to cfg.MaxConcurrency items concurrently.
+// The first non-retryable (or retry-exhausted) error cancels the context passed to every other
+// in-flight and not-yet-started call, so siblings stop working against the API as soon as
+// possible. On completion, Run returns nil if every item succeeded, or a *multierror.Error
+// listing one error per failed item (identified by its %v representation) so operators can see
+// every failure - e.g. every naming conflict - at once instead of one-at-a-time.
+func Run[T any](ctx context.Context, items []T, cfg Config, fn func(ctx context.Context, item T) error) error {
+	maxConcurrency := cfg.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultMaxConcurrency
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	var mu sync.Mutex
+	var failures *multierror.Error
+
+	for _, item := range items {
+		item := item
+		group.Go(func() error {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			err := runWithRetry(groupCtx, item, maxAttempts, fn)
+			if err != nil {
+				mu.Lock()
+				failures = multierror.Append(failures, fmt.Errorf("%v: %w", item, err))
+				mu.Unlock()
+			}
+			return err
+		})
+	}
+
+	// errgroup.Wait's return value only carries the first error - the full set of failures is
+	// accumulated in `failures` above so every failed item is reported, not just the first.
+	_ = group.Wait()
+
+	if failures != nil {
+		return failures
+	}
+	return nil
+}
+
+// runWithRetry attempts fn up to maxAttempts times, retrying only errors wrapped in a
+// RetryableError, and waiting between attempts per the error's RetryAfter or an exponential
+// backoff with jitter otherwise.
+func runWithRetry[T any](ctx context.Context, item T, maxAttempts int, fn func(ctx context.Context, item T) error) error {
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = fn(ctx, item)
+		if lastErr == nil {
+			return nil
+		}
+
+		var retryable RetryableError
+		if !errors.As(lastErr, &retryable) || attempt == maxAttempts {
+			return lastErr
+		}
+
+		wait := retryable.RetryAfter
+		if wait <= 0 {
+			wait = backoff(attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return lastErr
+}
+
+// backoff returns an exponential backoff duration with jitter for the given 1-indexed attempt.
+func backoff(attempt int) time.Duration {
+	base := time.Duration(1<<uint(attempt-1)) * time.Second
+	return base + time.Duration(rand.Int63n(int64(base)))
+}
+
+// httpStatusError is satisfied by the error types returned from go-azure-sdk clients, which
+// expose the status code of the underlying response.
+type httpStatusError interface {
+	error
+	StatusCode() int
+}
+
+// ClassifyAzureError wraps err in a RetryableError if it represents a transient Azure response -
+// a 429 (honoring the Retry-After header if present) or a 5xx - so Run retries it instead of
+// failing the batch outright. Any other error, including a nil err, is returned unchanged.
+func ClassifyAzureError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr httpStatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	statusCode := statusErr.StatusCode()
+	if statusCode != 429 && (statusCode < 500 || statusCode > 599) {
+		return err
+	}
+
+	retryAfter := time.Duration(0)
+	var headerErr interface{ RetryAfter() time.Duration }
+	if errors.As(err, &headerErr) {
+		retryAfter = headerErr.RetryAfter()
+	}
+
+	return RetryableError{Err: err, RetryAfter: retryAfter}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bulkop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun_allSucceed(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	var calls int32
+	err := Run(context.Background(), items, Config{}, func(_ context.Context, _ int) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+	if calls != int32(len(items)) {
+		t.Fatalf("expected %d calls, got %d", len(items), calls)
+	}
+}
+
+func TestRun_boundsConcurrency(t *testing.T) {
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	var inFlight, maxInFlight int32
+	err := Run(context.Background(), items, Config{MaxConcurrency: 3}, func(_ context.Context, _ int) error {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %+v", err)
+	}
+	if maxInFlight > 3 {
+		t.Fatalf("expected at most 3 concurrent calls, observed %d", maxInFlight)
+	}
+}
+
+func TestRun_reportsEveryFailure(t *testing.T) {
+	items := []string{"a", "b", "c"}
+
+	err := Run(context.Background(), items, Config{}, func(_ context.Context, item string) error {
+		if item == "b" {
+			return nil
+		}
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := err.Error(); !strings.Contains(got, "a:") || !strings.Contains(got, "c:") || strings.Contains(got, "b:") {
+		t.Fatalf("expected failures for items a and c only, got: %s", got)
+	}
+}
+
+func TestRun_cancelsPeersOnError(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	var started, ranAfterCancel int32
+	err := Run(context.Background(), items, Config{MaxConcurrency: 50}, func(ctx context.Context, item int) error {
+		atomic.AddInt32(&started, 1)
+		if item == 0 {
+			return errors.New("boom")
+		}
+
+		// give the first goroutine a chance to fail and cancel the group context
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() != nil {
+			atomic.AddInt32(&ranAfterCancel, 1)
+		}
+		return ctx.Err()
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if ranAfterCancel == 0 {
+		t.Fatal("expected at least one peer to observe context cancellation")
+	}
+}
+
+func TestRun_retriesRetryableErrors(t *testing.T) {
+	var attempts int32
+	err := Run(context.Background(), []int{1}, Config{MaxAttempts: 3}, func(_ context.Context, _ int) error {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			return RetryableError{Err: errors.New("transient"), RetryAfter: time.Millisecond}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected eventual success, got %+v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
@@ -0,0 +1,375 @@
+package keyvault
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/jackofallops/kermit/sdk/keyvault/7.4/keyvault"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &KeyVaultCertificateEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &KeyVaultCertificateEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &KeyVaultCertificateEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &KeyVaultCertificateEphemeralResource{}
+)
+
+// NewKeyVaultCertificateEphemeralResource is registered with the provider's list of ephemeral
+// resources in Registration.EphemeralResources, alongside NewKeyVaultSecretEphemeralResource.
+func NewKeyVaultCertificateEphemeralResource() ephemeral.EphemeralResource {
+	return &KeyVaultCertificateEphemeralResource{}
+}
+
+const keyVaultCertificateEphemeralPrivateDataKey = "certificate"
+
+// pkcs12ContentType is the content type of the backing Secret when the certificate was imported
+// or issued as a PFX; anything else is treated as PEM.
+const pkcs12ContentType = "application/x-pkcs12"
+
+type KeyVaultCertificateEphemeralResource struct {
+	client *clients.Client
+}
+
+type KeyVaultCertificateEphemeralResourceModel struct {
+	Name           types.String `tfsdk:"name"`
+	KeyVaultID     types.String `tfsdk:"key_vault_id"`
+	Version        types.String `tfsdk:"version"`
+	Thumbprint     types.String `tfsdk:"thumbprint"`
+	PEMCertificate types.String `tfsdk:"pem_certificate"`
+	PEMPrivateKey  types.String `tfsdk:"pem_private_key"`
+	PEMChain       types.String `tfsdk:"pem_chain"`
+}
+
+// keyVaultCertificateEphemeralPrivateData is stashed in OpenResponse.Private so Renew can
+// re-fetch the certificate without re-parsing the Key Vault ID out of config.
+type keyVaultCertificateEphemeralPrivateData struct {
+	VaultBaseURL string `json:"vault_base_url"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Pinned       bool   `json:"pinned"`
+}
+
+func (e *KeyVaultCertificateEphemeralResource) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = "azurerm_key_vault_certificate"
+}
+
+func (e *KeyVaultCertificateEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+
+			"key_vault_id": schema.StringAttribute{
+				Required: true,
+			},
+
+			"version": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The version of the Certificate to retrieve. If omitted, the latest version is used and re-resolved on every renewal.",
+			},
+
+			"thumbprint": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"pem_certificate": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"pem_private_key": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM-encoded private key, if the Certificate is exportable.",
+			},
+
+			"pem_chain": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func (e *KeyVaultCertificateEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*clients.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Ephemeral Resource Configure Type", fmt.Sprintf("Expected *clients.Client, got: %T", req.ProviderData))
+		return
+	}
+
+	e.client = client
+}
+
+func (e *KeyVaultCertificateEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data KeyVaultCertificateEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyVaultId, err := commonids.ParseKeyVaultID(data.KeyVaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid `key_vault_id`", err.Error())
+		return
+	}
+
+	keyVaultBaseUri, err := e.client.KeyVault.BaseUriForKeyVault(ctx, *keyVaultId)
+	if err != nil {
+		resp.Diagnostics.AddError("Retrieving Key Vault Base URI", fmt.Sprintf("retrieving %s: %+v", *keyVaultId, err))
+		return
+	}
+
+	name := data.Name.ValueString()
+	requestedVersion := data.Version.ValueString()
+	pinned := requestedVersion != ""
+
+	resolvedVersion, renewAt, closeToRotation, err := e.populate(ctx, &data, *keyVaultBaseUri, name, requestedVersion)
+	if err != nil {
+		resp.Diagnostics.AddError("Retrieving Certificate", err.Error())
+		return
+	}
+	if closeToRotation {
+		resp.Diagnostics.AddWarning("Certificate Close to Auto-Rotation", fmt.Sprintf("%q is managed by an issuer policy and is close to its auto-rotation window - the material returned by this ephemeral resource is short-lived", name))
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.RenewAt = renewAt
+
+	private := keyVaultCertificateEphemeralPrivateData{
+		VaultBaseURL: *keyVaultBaseUri,
+		Name:         name,
+		Version:      resolvedVersion,
+		Pinned:       pinned,
+	}
+	privateBytes, err := json.Marshal(private)
+	if err != nil {
+		resp.Diagnostics.AddError("Encoding Private State", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, keyVaultCertificateEphemeralPrivateDataKey, privateBytes)...)
+}
+
+func (e *KeyVaultCertificateEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	privateBytes, diags := req.Private.GetKey(ctx, keyVaultCertificateEphemeralPrivateDataKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var private keyVaultCertificateEphemeralPrivateData
+	if err := json.Unmarshal(privateBytes, &private); err != nil {
+		resp.Diagnostics.AddError("Decoding Private State", err.Error())
+		return
+	}
+
+	lookupVersion := ""
+	if private.Pinned {
+		lookupVersion = private.Version
+	}
+
+	var data KeyVaultCertificateEphemeralResourceModel
+	resolvedVersion, renewAt, closeToRotation, err := e.populate(ctx, &data, private.VaultBaseURL, private.Name, lookupVersion)
+	if err != nil {
+		resp.Diagnostics.AddError("Retrieving Certificate", err.Error())
+		return
+	}
+	if closeToRotation {
+		resp.Diagnostics.AddWarning("Certificate Close to Auto-Rotation", fmt.Sprintf("%q is managed by an issuer policy and is close to its auto-rotation window - the material returned by this ephemeral resource is short-lived", private.Name))
+	}
+
+	if !private.Pinned && resolvedVersion != private.Version {
+		resp.Diagnostics.AddWarning("Key Vault Certificate Rotated", fmt.Sprintf("the latest version of %q is now %q (was %q) - downstream consumers of this ephemeral value should expect it to have rotated", private.Name, resolvedVersion, private.Version))
+		private.Version = resolvedVersion
+	}
+
+	resp.RenewAt = renewAt
+
+	updatedBytes, err := json.Marshal(private)
+	if err != nil {
+		resp.Diagnostics.AddError("Encoding Private State", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, keyVaultCertificateEphemeralPrivateDataKey, updatedBytes)...)
+}
+
+func (e *KeyVaultCertificateEphemeralResource) Close(_ context.Context, _ ephemeral.CloseRequest, _ *ephemeral.CloseResponse) {
+	// nothing is provisioned by this ephemeral resource, so there is nothing to tear down
+}
+
+// populate fetches the certificate (for its thumbprint, validity window and issuer policy) and
+// the backing secret (for the key material), decodes the PFX/PEM payload into data, and returns
+// the resolved version, when the cached material should be renewed, and whether the certificate's
+// issuer policy indicates it is close to auto-rotation.
+func (e *KeyVaultCertificateEphemeralResource) populate(ctx context.Context, data *KeyVaultCertificateEphemeralResourceModel, vaultBaseUrl, name, version string) (resolvedVersion string, renewAt time.Time, closeToRotation bool, err error) {
+	cert, err := e.client.KeyVault.ManagementClient.GetCertificate(ctx, vaultBaseUrl, name, version)
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("retrieving %q from %q: %+v", name, vaultBaseUrl, err)
+	}
+
+	resolvedVersion = resolveSecretVersion(cert.ID)
+
+	secret, err := e.client.KeyVault.ManagementClient.GetSecret(ctx, vaultBaseUrl, name, resolvedVersion)
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("retrieving backing secret for %q from %q: %+v", name, vaultBaseUrl, err)
+	}
+
+	certificatePEM, privateKeyPEM, chainPEM, err := decodeCertificateMaterial(secret)
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("decoding certificate material for %q: %+v", name, err)
+	}
+
+	data.Name = types.StringValue(name)
+	data.Version = types.StringValue(resolvedVersion)
+	data.Thumbprint = types.StringValue(thumbprintOf(cert.X509Thumbprint))
+	data.PEMCertificate = types.StringValue(certificatePEM)
+	data.PEMPrivateKey = types.StringValue(privateKeyPEM)
+	data.PEMChain = types.StringValue(chainPEM)
+
+	renewAt = renewAtFor(certExpiresOn(cert.Attributes))
+	closeToRotation = policyIndicatesImminentAutoRotation(cert.Policy, cert.Attributes)
+
+	return resolvedVersion, renewAt, closeToRotation, nil
+}
+
+// certExpiresOn extracts the certificate's expiry as a time.Time, or nil if it has none.
+func certExpiresOn(attributes *keyvault.CertificateAttributes) *time.Time {
+	if attributes == nil || attributes.Expires == nil {
+		return nil
+	}
+	t := time.Time(*attributes.Expires)
+	return &t
+}
+
+// decodeCertificateMaterial extracts the leaf certificate, private key (if exportable) and
+// remaining chain certificates as PEM from the certificate's backing Secret, which Key Vault
+// stores either as a PKCS#12 (PFX) blob or as a concatenated PEM payload.
+func decodeCertificateMaterial(secret keyvault.SecretBundle) (certificatePEM, privateKeyPEM, chainPEM string, err error) {
+	value := pointer.From(secret.Value)
+
+	if pointer.From(secret.ContentType) == pkcs12ContentType {
+		raw, decodeErr := base64.StdEncoding.DecodeString(value)
+		if decodeErr != nil {
+			return "", "", "", fmt.Errorf("decoding PKCS#12 payload: %+v", decodeErr)
+		}
+
+		blocks, decodeErr := pkcs12.ToPEM(raw, "")
+		if decodeErr != nil {
+			return "", "", "", fmt.Errorf("converting PKCS#12 payload to PEM: %+v", decodeErr)
+		}
+
+		var chainBlocks []*pem.Block
+		for _, block := range blocks {
+			switch block.Type {
+			case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+				privateKeyPEM += string(pem.EncodeToMemory(block))
+			case "CERTIFICATE":
+				if certificatePEM == "" {
+					certificatePEM = string(pem.EncodeToMemory(block))
+				} else {
+					chainBlocks = append(chainBlocks, block)
+				}
+			}
+		}
+		for _, block := range chainBlocks {
+			chainPEM += string(pem.EncodeToMemory(block))
+		}
+		return certificatePEM, privateKeyPEM, chainPEM, nil
+	}
+
+	// PEM content type (or unspecified): the secret value is the key followed by the certificate
+	// chain, leaf first.
+	remaining := value
+	var certs []string
+	for {
+		block, rest := pem.Decode([]byte(remaining))
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+			privateKeyPEM += string(pem.EncodeToMemory(block))
+		case "CERTIFICATE":
+			certs = append(certs, string(pem.EncodeToMemory(block)))
+		}
+		remaining = string(rest)
+	}
+	if len(certs) > 0 {
+		certificatePEM = certs[0]
+		chainPEM = strings.Join(certs[1:], "")
+	}
+	return certificatePEM, privateKeyPEM, chainPEM, nil
+}
+
+func thumbprintOf(raw *[]byte) string {
+	if raw == nil {
+		return ""
+	}
+	return strings.ToUpper(hex.EncodeToString(*raw))
+}
+
+// policyIndicatesImminentAutoRotation reports whether the certificate's issuer policy has an
+// auto-renew lifetime action whose trigger point - expressed as either `days_before_expiry` or
+// `lifetime_percentage` of the certificate's `not_before`/`expires` window - falls within
+// renewBuffer of now (or has already passed), meaning the material returned here should be
+// treated by the caller as short-lived.
+func policyIndicatesImminentAutoRotation(policy *keyvault.CertificatePolicy, attributes *keyvault.CertificateAttributes) bool {
+	if policy == nil || policy.LifetimeActions == nil || attributes == nil || attributes.Expires == nil {
+		return false
+	}
+	expires := time.Time(*attributes.Expires)
+
+	var notBefore time.Time
+	if attributes.NotBefore != nil {
+		notBefore = time.Time(*attributes.NotBefore)
+	}
+
+	for _, action := range *policy.LifetimeActions {
+		if action.Action == nil || action.Action.ActionType != keyvault.AutoRenew || action.Trigger == nil {
+			continue
+		}
+
+		var triggersAt time.Time
+		switch {
+		case action.Trigger.DaysBeforeExpiry != nil:
+			triggersAt = expires.AddDate(0, 0, -int(*action.Trigger.DaysBeforeExpiry))
+		case action.Trigger.LifetimePercentage != nil && !notBefore.IsZero():
+			lifetime := expires.Sub(notBefore)
+			elapsed := lifetime * time.Duration(*action.Trigger.LifetimePercentage) / 100
+			triggersAt = notBefore.Add(elapsed)
+		default:
+			continue
+		}
+
+		if !time.Now().Before(triggersAt.Add(-renewBuffer)) {
+			return true
+		}
+	}
+
+	return false
+}
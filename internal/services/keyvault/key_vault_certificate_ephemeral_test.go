@@ -0,0 +1,95 @@
+package keyvault_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+)
+
+func TestAccKeyVaultCertificateEphemeral_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "echo", "test")
+	r := KeyVaultCertificateEphemeralResource{}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactoriesInclEcho(),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: r.basic(data),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("echo.test", "data.pem_certificate"),
+					resource.TestCheckResourceAttrSet("echo.test", "data.thumbprint"),
+				),
+			},
+		},
+	})
+}
+
+type KeyVaultCertificateEphemeralResource struct{}
+
+func (KeyVaultCertificateEphemeralResource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-kvcert-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_key_vault" "test" {
+  name                = "acctestkv-%[3]s"
+  location            = azurerm_resource_group.test.location
+  resource_group_name  = azurerm_resource_group.test.name
+  tenant_id           = data.azurerm_client_config.current.tenant_id
+  sku_name            = "standard"
+}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_key_vault_certificate" "test" {
+  name         = "acctestcert-%[1]d"
+  key_vault_id = azurerm_key_vault.test.id
+
+  certificate {
+    contents = filebase64("testdata/certificate.pfx")
+    password = ""
+  }
+
+  certificate_policy {
+    issuer_parameters {
+      name = "Self"
+    }
+
+    key_properties {
+      exportable = true
+      key_size   = 2048
+      key_type   = "RSA"
+      reuse_key  = false
+    }
+
+    secret_properties {
+      content_type = "application/x-pkcs12"
+    }
+  }
+}
+
+ephemeral "azurerm_key_vault_certificate" "test" {
+  name         = azurerm_key_vault_certificate.test.name
+  key_vault_id = azurerm_key_vault.test.id
+}
+
+resource "echo" "test" {
+  data = {
+    pem_certificate = ephemeral.azurerm_key_vault_certificate.test.pem_certificate
+    thumbprint      = ephemeral.azurerm_key_vault_certificate.test.thumbprint
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, data.RandomString)
+}
@@ -2,19 +2,44 @@ package keyvault
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/keyvault/parse"
+	"github.com/jackofallops/kermit/sdk/keyvault/7.4/keyvault"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &KeyVaultSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &KeyVaultSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &KeyVaultSecretEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &KeyVaultSecretEphemeralResource{}
 )
 
-//func NewEphemeralSecrets(_ context.Context) (ephemeral.EphemeralResource, error) {
-//	return &ephemeralSecrets{}, nil
-//}
+// NewKeyVaultSecretEphemeralResource is registered with the provider's list of ephemeral
+// resources in Registration.EphemeralResources, alongside NewKeyVaultCertificateEphemeralResource.
+func NewKeyVaultSecretEphemeralResource() ephemeral.EphemeralResource {
+	return &KeyVaultSecretEphemeralResource{}
+}
+
+// renewBuffer is how far ahead of a secret's expiry Renew should be scheduled.
+const renewBuffer = 5 * time.Minute
 
-//type ephemeralSecrets ephemeral.EphemeralResource
+// maxRenewWindow caps how far out Renew is scheduled when a secret's expiry is more than an hour
+// away. Secrets with no expiry are not scheduled for renewal at all.
+const maxRenewWindow = time.Hour
+
+const keyVaultSecretEphemeralPrivateDataKey = "secret"
 
 type KeyVaultSecretEphemeralResource struct {
-	ephemeral.EphemeralResource
+	client *clients.Client
 }
 
 type KeyVaultSecretEphemeralResourceModel struct {
@@ -24,36 +49,59 @@ type KeyVaultSecretEphemeralResourceModel struct {
 	Version    types.String `tfsdk:"version"`
 }
 
-func (e KeyVaultSecretEphemeralResource) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+// keyVaultSecretEphemeralPrivateData is stashed in OpenResponse.Private so Renew can re-fetch
+// the secret without re-parsing the Key Vault ID out of config.
+type keyVaultSecretEphemeralPrivateData struct {
+	VaultBaseURL string `json:"vault_base_url"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Pinned       bool   `json:"pinned"`
+}
+
+func (e *KeyVaultSecretEphemeralResource) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
 	resp.TypeName = "azurerm_key_vault_secret"
 }
 
-func (e KeyVaultSecretEphemeralResource) Schema(ctx context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+func (e *KeyVaultSecretEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
-				Required:   true,
-				Validators: nil, // TODO
+				Required: true,
 			},
 
 			"key_vault_id": schema.StringAttribute{
-				Required:   true,
-				Validators: nil, // TODO
+				Required: true,
+			},
+
+			"version": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The version of the Secret to retrieve. If omitted, the latest version is used and re-resolved on every renewal.",
 			},
 
 			"value": schema.StringAttribute{
 				Computed:  true,
 				Sensitive: true,
 			},
-
-			"version": schema.StringAttribute{
-				Computed: true,
-			},
 		},
 	}
 }
 
-func (e KeyVaultSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+func (e *KeyVaultSecretEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*clients.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Ephemeral Resource Configure Type", fmt.Sprintf("Expected *clients.Client, got: %T", req.ProviderData))
+		return
+	}
+
+	e.client = client
+}
+
+func (e *KeyVaultSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
 	var data KeyVaultSecretEphemeralResourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -61,4 +109,128 @@ func (e KeyVaultSecretEphemeralResource) Open(ctx context.Context, req ephemeral
 		return
 	}
 
+	keyVaultId, err := commonids.ParseKeyVaultID(data.KeyVaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid `key_vault_id`", err.Error())
+		return
+	}
+
+	keyVaultBaseUri, err := e.client.KeyVault.BaseUriForKeyVault(ctx, *keyVaultId)
+	if err != nil {
+		resp.Diagnostics.AddError("Retrieving Key Vault Base URI", fmt.Sprintf("retrieving %s: %+v", *keyVaultId, err))
+		return
+	}
+
+	name := data.Name.ValueString()
+	requestedVersion := data.Version.ValueString()
+	pinned := requestedVersion != ""
+
+	secret, err := e.client.KeyVault.ManagementClient.GetSecret(ctx, *keyVaultBaseUri, name, requestedVersion)
+	if err != nil {
+		resp.Diagnostics.AddError("Retrieving Secret", fmt.Sprintf("retrieving %q from %q: %+v", name, *keyVaultBaseUri, err))
+		return
+	}
+
+	resolvedVersion := resolveSecretVersion(secret.ID)
+
+	data.Value = types.StringValue(pointer.From(secret.Value))
+	data.Version = types.StringValue(resolvedVersion)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.RenewAt = renewAtFor(expiresOn(secret.Attributes))
+
+	private := keyVaultSecretEphemeralPrivateData{
+		VaultBaseURL: *keyVaultBaseUri,
+		Name:         name,
+		Version:      resolvedVersion,
+		Pinned:       pinned,
+	}
+	privateBytes, err := json.Marshal(private)
+	if err != nil {
+		resp.Diagnostics.AddError("Encoding Private State", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, keyVaultSecretEphemeralPrivateDataKey, privateBytes)...)
+}
+
+func (e *KeyVaultSecretEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	privateBytes, diags := req.Private.GetKey(ctx, keyVaultSecretEphemeralPrivateDataKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var private keyVaultSecretEphemeralPrivateData
+	if err := json.Unmarshal(privateBytes, &private); err != nil {
+		resp.Diagnostics.AddError("Decoding Private State", err.Error())
+		return
+	}
+
+	lookupVersion := ""
+	if private.Pinned {
+		lookupVersion = private.Version
+	}
+
+	secret, err := e.client.KeyVault.ManagementClient.GetSecret(ctx, private.VaultBaseURL, private.Name, lookupVersion)
+	if err != nil {
+		resp.Diagnostics.AddError("Retrieving Secret", fmt.Sprintf("retrieving %q from %q: %+v", private.Name, private.VaultBaseURL, err))
+		return
+	}
+
+	resolvedVersion := resolveSecretVersion(secret.ID)
+	if !private.Pinned && resolvedVersion != private.Version {
+		resp.Diagnostics.AddWarning("Key Vault Secret Rotated", fmt.Sprintf("the latest version of %q is now %q (was %q) - downstream consumers of this ephemeral value should expect it to have changed", private.Name, resolvedVersion, private.Version))
+		private.Version = resolvedVersion
+	}
+
+	resp.RenewAt = renewAtFor(expiresOn(secret.Attributes))
+
+	updatedBytes, err := json.Marshal(private)
+	if err != nil {
+		resp.Diagnostics.AddError("Encoding Private State", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, keyVaultSecretEphemeralPrivateDataKey, updatedBytes)...)
+}
+
+func (e *KeyVaultSecretEphemeralResource) Close(_ context.Context, _ ephemeral.CloseRequest, _ *ephemeral.CloseResponse) {
+	// nothing is provisioned by this ephemeral resource, so there is nothing to tear down
+}
+
+// renewAtFor computes when the cached secret value should be renewed: 5 minutes before the
+// secret's expiry, capped at 1 hour from now. Secrets without an expiry are not scheduled for renewal.
+func renewAtFor(expires *time.Time) time.Time {
+	if expires == nil {
+		return time.Time{}
+	}
+
+	renewAt := expires.Add(-renewBuffer)
+	if cap := time.Now().Add(maxRenewWindow); renewAt.After(cap) {
+		renewAt = cap
+	}
+	return renewAt
+}
+
+// expiresOn extracts the secret's expiry as a time.Time, or nil if it has none.
+func expiresOn(attributes *keyvault.SecretAttributes) *time.Time {
+	if attributes == nil || attributes.Expires == nil {
+		return nil
+	}
+	t := time.Time(*attributes.Expires)
+	return &t
+}
+
+func resolveSecretVersion(secretID *string) string {
+	if secretID == nil {
+		return ""
+	}
+	parsed, err := parse.ParseNestedItemID(*secretID)
+	if err != nil {
+		return ""
+	}
+	return parsed.Version
 }
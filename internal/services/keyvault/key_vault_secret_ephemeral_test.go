@@ -0,0 +1,137 @@
+package keyvault_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+)
+
+func TestAccKeyVaultSecretEphemeral_versionPinning(t *testing.T) {
+	data := acceptance.BuildTestData(t, "echo", "test")
+	r := KeyVaultSecretEphemeralResource{}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactoriesInclEcho(),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: r.versionPinned(data),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("echo.test", "data.value"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyVaultSecretEphemeral_expiryDrivenRenewal(t *testing.T) {
+	data := acceptance.BuildTestData(t, "echo", "test")
+	r := KeyVaultSecretEphemeralResource{}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactoriesInclEcho(),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: r.expiring(data),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("echo.test", "data.value"),
+				),
+			},
+			// each TestStep is its own `terraform apply`, so this re-opens the ephemeral resource
+			// rather than exercising Renew mid-operation (that would need a second consumer of the
+			// same ephemeral instance separated by a real delay within a single apply). Sleeping
+			// past the secret's renew_at here at least confirms the ephemeral resource keeps
+			// working once that window has elapsed - the secret's value is static, so this does not
+			// verify that a rotated value would be picked up.
+			{
+				// expiring() sets a 20m expiration_date, counted from when the secret is created, and
+				// renew_at is 5 minutes before expiry (see renewBuffer in key_vault_secret_ephemeral.go),
+				// so the 16m sleep after step 1 passes renew_at while leaving a few minutes before the
+				// secret itself expires.
+				PreConfig: func() { time.Sleep(16 * time.Minute) },
+				Config:    r.expiring(data),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("echo.test", "data.value"),
+				),
+			},
+		},
+	})
+}
+
+type KeyVaultSecretEphemeralResource struct{}
+
+func (KeyVaultSecretEphemeralResource) template(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-kv-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_key_vault" "test" {
+  name                = "acctestkv-%[3]s"
+  location            = azurerm_resource_group.test.location
+  resource_group_name  = azurerm_resource_group.test.name
+  tenant_id           = data.azurerm_client_config.current.tenant_id
+  sku_name            = "standard"
+}
+
+data "azurerm_client_config" "current" {}
+`, data.RandomInteger, data.Locations.Primary, data.RandomString)
+}
+
+func (r KeyVaultSecretEphemeralResource) versionPinned(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_key_vault_secret" "test" {
+  name         = "acctestsecret-%[2]d"
+  value        = "rick-and-morty"
+  key_vault_id = azurerm_key_vault.test.id
+}
+
+ephemeral "azurerm_key_vault_secret" "test" {
+  name         = azurerm_key_vault_secret.test.name
+  key_vault_id = azurerm_key_vault.test.id
+  version      = azurerm_key_vault_secret.test.version
+}
+
+resource "echo" "test" {
+  data = ephemeral.azurerm_key_vault_secret.test.value
+}
+`, r.template(data), data.RandomInteger)
+}
+
+func (r KeyVaultSecretEphemeralResource) expiring(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_key_vault_secret" "test" {
+  name            = "acctestsecret-%[2]d"
+  value           = "rick-and-morty"
+  key_vault_id    = azurerm_key_vault.test.id
+  expiration_date = timeadd(timestamp(), "20m")
+}
+
+ephemeral "azurerm_key_vault_secret" "test" {
+  name         = azurerm_key_vault_secret.test.name
+  key_vault_id = azurerm_key_vault.test.id
+}
+
+resource "echo" "test" {
+  data = ephemeral.azurerm_key_vault_secret.test.value
+}
+`, r.template(data), data.RandomInteger)
+}
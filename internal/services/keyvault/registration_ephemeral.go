@@ -0,0 +1,14 @@
+package keyvault
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+)
+
+// EphemeralResources is part of sdk.TypedServiceRegistrationWithAGitHubLabel - it returns every
+// ephemeral resource this service package exposes to the provider.
+func (r Registration) EphemeralResources() []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewKeyVaultSecretEphemeralResource,
+		NewKeyVaultCertificateEphemeralResource,
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package postgres
+
+import (
+	"fmt"
+	"hash/crc32"
+	"net"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+// expandRules resolves every `rule` block's `cidr` shorthand into start/end IP addresses and fans
+// every `cidr_rule` block out into one Rule per CIDR, returning the combined, fully-expanded set
+// of rules to send to Azure.
+func expandRules(rules []Rule, cidrRules []CIDRRule) ([]Rule, error) {
+	expanded := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		normalized, err := normalizeRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %+v", rule.Name, err)
+		}
+		expanded = append(expanded, normalized)
+	}
+
+	for _, cidrRule := range cidrRules {
+		for _, cidr := range cidrRule.CIDRs {
+			start, end, err := cidrToRange(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("cidr_rule %q: %+v", cidrRule.NamePrefix, err)
+			}
+			expanded = append(expanded, Rule{
+				Name:           ipRangeName(cidrRule.NamePrefix, cidr),
+				StartIPAddress: start,
+				EndIPAddress:   end,
+				CIDR:           cidr,
+			})
+		}
+	}
+
+	return expanded, nil
+}
+
+// normalizeRule fills in start_ip_address/end_ip_address from cidr (or vice versa) and validates
+// that exactly one of `cidr` or the `start_ip_address`/`end_ip_address` pair was specified.
+func normalizeRule(rule Rule) (Rule, error) {
+	hasCIDR := rule.CIDR != ""
+	hasRange := rule.StartIPAddress != "" || rule.EndIPAddress != ""
+
+	switch {
+	case hasCIDR && hasRange:
+		return Rule{}, fmt.Errorf("`cidr` and `start_ip_address`/`end_ip_address` are mutually exclusive")
+	case hasCIDR:
+		start, end, err := cidrToRange(rule.CIDR)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.StartIPAddress = start
+		rule.EndIPAddress = end
+	case rule.StartIPAddress == "" || rule.EndIPAddress == "":
+		return Rule{}, fmt.Errorf("either `cidr` or both `start_ip_address` and `end_ip_address` must be specified")
+	default:
+		if cidr, ok := rangeToCIDR(rule.StartIPAddress, rule.EndIPAddress); ok {
+			rule.CIDR = cidr
+		}
+	}
+
+	return rule, nil
+}
+
+// collapseCIDRRules groups rules read back from Azure into the `cidr_rule` blocks they were
+// previously fanned out from, matching by the stable name ipRangeName derives for each CIDR in
+// priorCIDRRules. Rules that don't match any prior `cidr_rule` (including plain `rule` blocks)
+// are returned unchanged in remaining.
+func collapseCIDRRules(rules []Rule, priorCIDRRules []CIDRRule) (remaining []Rule, cidrRules []CIDRRule) {
+	byName := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		byName[rule.Name] = rule
+	}
+
+	matched := make(map[string]struct{})
+	cidrRules = make([]CIDRRule, 0, len(priorCIDRRules))
+	for _, prior := range priorCIDRRules {
+		cidrs := make([]string, 0, len(prior.CIDRs))
+		for _, cidr := range prior.CIDRs {
+			name := ipRangeName(prior.NamePrefix, cidr)
+			if rule, ok := byName[name]; ok && rule.CIDR == cidr {
+				cidrs = append(cidrs, cidr)
+				matched[name] = struct{}{}
+			}
+		}
+		if len(cidrs) > 0 {
+			cidrRules = append(cidrRules, CIDRRule{NamePrefix: prior.NamePrefix, CIDRs: cidrs})
+		}
+	}
+
+	remaining = make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if _, ok := matched[rule.Name]; !ok {
+			remaining = append(remaining, rule)
+		}
+	}
+
+	return remaining, cidrRules
+}
+
+// ipAddressDiffSuppress treats equivalent IP address representations (e.g. an IPv4 address and
+// its IPv4-mapped IPv6 form) as unchanged - firewall rule ranges configured via `cidr` are expanded before
+// being sent to Azure, and should not show a perpetual diff against the range read back from it.
+func ipAddressDiffSuppress(_, old, new string, _ *pluginsdk.ResourceData) bool {
+	oldIP := net.ParseIP(old)
+	newIP := net.ParseIP(new)
+	if oldIP == nil || newIP == nil {
+		return false
+	}
+	return oldIP.Equal(newIP)
+}
+
+// cidrToRange expands a CIDR block into its inclusive start/end IPv4 addresses, e.g. "1.2.3.0/24"
+// becomes ("1.2.3.0", "1.2.3.255"), matching how the PostgreSQL flexible-server firewall rule API
+// expresses ranges.
+func cidrToRange(cidr string) (start, end string, err error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing %q as a CIDR: %+v", cidr, err)
+	}
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return "", "", fmt.Errorf("%q is not a valid IPv4 CIDR", cidr)
+	}
+
+	startIP := ip4.Mask(ipNet.Mask)
+	endIP := make(net.IP, len(startIP))
+	for i := range startIP {
+		endIP[i] = startIP[i] | ^ipNet.Mask[i]
+	}
+
+	return startIP.String(), endIP.String(), nil
+}
+
+// rangeToCIDR is the inverse of cidrToRange: if start/end represent an exact CIDR block (i.e. the
+// network and broadcast addresses of some prefix length), it returns that CIDR; otherwise ok is
+// false, since not every IP range is expressible as a single CIDR.
+func rangeToCIDR(start, end string) (cidr string, ok bool) {
+	startIP := net.ParseIP(start).To4()
+	endIP := net.ParseIP(end).To4()
+	if startIP == nil || endIP == nil {
+		return "", false
+	}
+
+	for prefixLen := 32; prefixLen >= 0; prefixLen-- {
+		mask := net.CIDRMask(prefixLen, 32)
+		network := startIP.Mask(mask)
+		if !network.Equal(startIP) {
+			continue
+		}
+
+		broadcast := make(net.IP, len(network))
+		for i := range network {
+			broadcast[i] = network[i] | ^mask[i]
+		}
+		if broadcast.Equal(endIP) {
+			return fmt.Sprintf("%s/%d", network.String(), prefixLen), true
+		}
+	}
+
+	return "", false
+}
+
+// ipRangeName derives a stable rule name for a CIDR fanned out from a `cidr_rule` block, keyed by
+// a hash of the CIDR rather than its position in the list so reordering the `cidrs` list doesn't
+// force unrelated rules to be recreated.
+func ipRangeName(namePrefix, cidr string) string {
+	return fmt.Sprintf("%s-%08x", namePrefix, crc32.ChecksumIEEE([]byte(cidr)))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package postgres
+
+import "testing"
+
+func TestCIDRToRange(t *testing.T) {
+	start, end, err := cidrToRange("1.2.3.0/24")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if start != "1.2.3.0" || end != "1.2.3.255" {
+		t.Fatalf("expected 1.2.3.0-1.2.3.255, got %s-%s", start, end)
+	}
+}
+
+func TestCIDRToRange_invalid(t *testing.T) {
+	if _, _, err := cidrToRange("not-a-cidr"); err == nil {
+		t.Fatal("expected an error for an invalid CIDR")
+	}
+}
+
+func TestRangeToCIDR(t *testing.T) {
+	cidr, ok := rangeToCIDR("1.2.3.0", "1.2.3.255")
+	if !ok {
+		t.Fatal("expected range to be representable as a CIDR")
+	}
+	if cidr != "1.2.3.0/24" {
+		t.Fatalf("expected 1.2.3.0/24, got %s", cidr)
+	}
+}
+
+func TestRangeToCIDR_notACIDR(t *testing.T) {
+	if _, ok := rangeToCIDR("1.2.3.5", "1.2.3.10"); ok {
+		t.Fatal("expected an arbitrary range to not be representable as a single CIDR")
+	}
+}
+
+func TestIPRangeName_stableAcrossReorder(t *testing.T) {
+	first := ipRangeName("allowlist", "1.2.3.0/24")
+	second := ipRangeName("allowlist", "1.2.3.0/24")
+	if first != second {
+		t.Fatalf("expected the same CIDR to always hash to the same name, got %s and %s", first, second)
+	}
+
+	other := ipRangeName("allowlist", "4.5.6.0/24")
+	if first == other {
+		t.Fatalf("expected different CIDRs to hash to different names")
+	}
+}
+
+func TestCollapseCIDRRules(t *testing.T) {
+	prior := []CIDRRule{
+		{NamePrefix: "allowlist", CIDRs: []string{"1.2.3.0/24", "4.5.6.0/24"}},
+	}
+
+	rules := []Rule{
+		{Name: ipRangeName("allowlist", "1.2.3.0/24"), StartIPAddress: "1.2.3.0", EndIPAddress: "1.2.3.255", CIDR: "1.2.3.0/24"},
+		{Name: "office", StartIPAddress: "9.9.9.9", EndIPAddress: "9.9.9.9", CIDR: "9.9.9.9/32"},
+	}
+
+	remaining, cidrRules := collapseCIDRRules(rules, prior)
+
+	if len(remaining) != 1 || remaining[0].Name != "office" {
+		t.Fatalf("expected only the unrelated rule to remain, got %+v", remaining)
+	}
+
+	if len(cidrRules) != 1 || cidrRules[0].NamePrefix != "allowlist" {
+		t.Fatalf("expected a single collapsed cidr_rule, got %+v", cidrRules)
+	}
+	if len(cidrRules[0].CIDRs) != 1 || cidrRules[0].CIDRs[0] != "1.2.3.0/24" {
+		t.Fatalf("expected only the CIDR still present on the server to be collapsed back in, got %+v", cidrRules[0].CIDRs)
+	}
+}
@@ -6,7 +6,6 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/hashicorp/go-azure-helpers/lang/pointer"
@@ -14,6 +13,7 @@ import (
 	"github.com/hashicorp/go-azure-sdk/resource-manager/postgresql/2022-12-01/firewallrules"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/postgresql/2023-06-01-preview/servers"
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/tf"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/bulkop"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/locks"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/services/postgres/validate"
@@ -22,21 +22,31 @@ import (
 )
 
 const maxConcurrency = 100
+const maxAttempts = 4
 
 type Rule struct {
 	Name           string `tfschema:"name"`
 	StartIPAddress string `tfschema:"start_ip_address"`
 	EndIPAddress   string `tfschema:"end_ip_address"`
+	CIDR           string `tfschema:"cidr"`
+}
+
+type CIDRRule struct {
+	NamePrefix string   `tfschema:"name_prefix"`
+	CIDRs      []string `tfschema:"cidrs"`
 }
 
 type FlexibleServerFirewallRulesModel struct {
-	ServerID string `tfschema:"server_id"`
-	Rule     []Rule `tfschema:"rule"`
+	ServerID string     `tfschema:"server_id"`
+	Rule     []Rule     `tfschema:"rule"`
+	CIDRRule []CIDRRule `tfschema:"cidr_rule"`
+	Managed  bool       `tfschema:"managed"`
 }
 
 var (
-	_ sdk.Resource           = FlexibleServerFirewallRulesResource{}
-	_ sdk.ResourceWithUpdate = FlexibleServerFirewallRulesResource{}
+	_ sdk.Resource                   = FlexibleServerFirewallRulesResource{}
+	_ sdk.ResourceWithUpdate         = FlexibleServerFirewallRulesResource{}
+	_ sdk.ResourceWithCustomImporter = FlexibleServerFirewallRulesResource{}
 )
 
 type FlexibleServerFirewallRulesResource struct{}
@@ -51,9 +61,10 @@ func (r FlexibleServerFirewallRulesResource) Arguments() map[string]*pluginsdk.S
 		},
 
 		"rule": {
-			Type:     pluginsdk.TypeSet,
-			Required: true,
-			MinItems: 1,
+			Type:         pluginsdk.TypeSet,
+			Optional:     true,
+			MinItems:     1,
+			AtLeastOneOf: []string{"rule", "cidr_rule"},
 			Elem: &pluginsdk.Resource{
 				Schema: map[string]*pluginsdk.Schema{
 					"name": {
@@ -63,24 +74,80 @@ func (r FlexibleServerFirewallRulesResource) Arguments() map[string]*pluginsdk.S
 					},
 
 					"end_ip_address": {
-						Type:         pluginsdk.TypeString,
-						Required:     true,
-						ValidateFunc: validation.IsIPAddress,
+						Type:             pluginsdk.TypeString,
+						Optional:         true,
+						Computed:         true,
+						ValidateFunc:     validation.IsIPAddress,
+						DiffSuppressFunc: ipAddressDiffSuppress,
 					},
 
 					"start_ip_address": {
+						Type:             pluginsdk.TypeString,
+						Optional:         true,
+						Computed:         true,
+						ValidateFunc:     validation.IsIPAddress,
+						DiffSuppressFunc: ipAddressDiffSuppress,
+					},
+
+					"cidr": {
+						Type:         pluginsdk.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.IsCIDR,
+					},
+				},
+			},
+		},
+
+		"cidr_rule": {
+			Type:         pluginsdk.TypeList,
+			Optional:     true,
+			MinItems:     1,
+			AtLeastOneOf: []string{"rule", "cidr_rule"},
+			Elem: &pluginsdk.Resource{
+				Schema: map[string]*pluginsdk.Schema{
+					"name_prefix": {
 						Type:         pluginsdk.TypeString,
 						Required:     true,
-						ValidateFunc: validation.IsIPAddress,
+						ValidateFunc: validate.FlexibleServerFirewallRuleName,
+					},
+
+					"cidrs": {
+						Type:     pluginsdk.TypeList,
+						Required: true,
+						MinItems: 1,
+						Elem: &pluginsdk.Schema{
+							Type:         pluginsdk.TypeString,
+							ValidateFunc: validation.IsCIDR,
+						},
 					},
 				},
 			},
 		},
+
+		"managed": {
+			Type:        pluginsdk.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Should this resource exclusively manage all firewall rules on the server? Defaults to `true`. When set to `false`, only the rules tracked by this resource are created, updated or removed - rules added out-of-band (e.g. by `azurerm_postgresql_flexible_server_firewall_rule`, the Azure Portal, or an \"Allow my client IP\" quick-add) are left untouched.",
+		},
 	}
 }
 
 func (r FlexibleServerFirewallRulesResource) Attributes() map[string]*pluginsdk.Schema {
-	return map[string]*pluginsdk.Schema{}
+	return map[string]*pluginsdk.Schema{
+		// tracked_rule_names is bookkeeping rather than configuration: a Computed attribute, so it is
+		// stored in state and visible there like any other attribute. It records the rule names
+		// this resource owns so that, in non-exclusive (`managed = false`) mode, Read,
+		// Update and Delete can tell owned rules apart from rules created out-of-band without
+		// re-deriving ownership from `rule`/`cidr_rule`, which Read must keep free of anything the
+		// user didn't configure.
+		"tracked_rule_names": {
+			Type:     pluginsdk.TypeSet,
+			Computed: true,
+			Elem:     &pluginsdk.Schema{Type: pluginsdk.TypeString},
+		},
+	}
 }
 
 func (r FlexibleServerFirewallRulesResource) ResourceType() string {
@@ -114,49 +181,28 @@ func (r FlexibleServerFirewallRulesResource) Create() sdk.ResourceFunc {
 			locks.ByName(id.FlexibleServerName, postgresqlFlexibleServerResourceName)
 			defer locks.UnlockByName(id.FlexibleServerName, postgresqlFlexibleServerResourceName)
 
-			listFirewallRulesResult, err := rulesClient.ListByServerComplete(ctx, *id)
-			if err != nil {
-				return err
-			}
-			if len(listFirewallRulesResult.Items) != 0 {
-				return tf.ImportAsExistsError(r.ResourceType(), id.ID())
-			}
-
-			firewallRules := make(map[string]firewallrules.FirewallRule)
-			for _, rule := range model.Rule {
-				fwRule := firewallrules.FirewallRule{
-					Properties: firewallrules.FirewallRuleProperties{
-						EndIPAddress:   rule.EndIPAddress,
-						StartIPAddress: rule.StartIPAddress,
-					},
+			if model.Managed {
+				listFirewallRulesResult, err := rulesClient.ListByServerComplete(ctx, *id)
+				if err != nil {
+					return err
+				}
+				if len(listFirewallRulesResult.Items) != 0 {
+					return tf.ImportAsExistsError(r.ResourceType(), id.ID())
 				}
-				fwRuleId := firewallrules.NewFirewallRuleID(id.SubscriptionId, id.ResourceGroupName, id.FlexibleServerName, rule.Name)
-				firewallRules[fwRuleId.ID()] = fwRule
 			}
 
-			maxRulesAtOnce := make(chan struct{}, maxConcurrency)
-			errs := make(chan error)
-			wg := &sync.WaitGroup{}
-
-			for i, f := range firewallRules {
-				wg.Add(1)
-				fid, _ := firewallrules.ParseFirewallRuleID(i)
-				go batchCreateOrUpdate(ctx, rulesClient, *fid, f, wg, maxRulesAtOnce, errs)
-
+			rules, err := expandRules(model.Rule, model.CIDRRule)
+			if err != nil {
+				return err
 			}
 
-			go func() {
-				wg.Wait()
-				close(errs)
-			}()
-
-			for chanErr := range errs {
-				if chanErr != nil {
-					return chanErr
-				}
+			if err := createOrUpdateRules(ctx, rulesClient, desiredFirewallRules(rules, *id)); err != nil {
+				return err
 			}
 
-			wg.Wait()
+			if err := metadata.ResourceData.Set("tracked_rule_names", trackedRuleNamesFrom(rules)); err != nil {
+				return fmt.Errorf("setting `tracked_rule_names`: %+v", err)
+			}
 
 			metadata.SetID(id)
 
@@ -165,6 +211,30 @@ func (r FlexibleServerFirewallRulesResource) Create() sdk.ResourceFunc {
 	}
 }
 
+// CustomImporter allows importing this resource directly onto a flexible server ID, e.g.
+// `terraform import azurerm_postgresql_flexible_server_firewall_rules.example
+// /subscriptions/.../flexibleServers/foo` - state is then hydrated from the server's current
+// rules by the regular Read.
+func (r FlexibleServerFirewallRulesResource) CustomImporter() sdk.ResourceRunFunc {
+	return func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+		if _, errs := servers.ValidateFlexibleServerID(metadata.ResourceData.Id(), "id"); len(errs) > 0 {
+			return fmt.Errorf("parsing %q as a Flexible Server ID: %+v", metadata.ResourceData.Id(), errs[0])
+		}
+
+		id, err := firewallrules.ParseFlexibleServerID(metadata.ResourceData.Id())
+		if err != nil {
+			return err
+		}
+
+		client := metadata.Client.Postgres.FlexibleServerFirewallRuleClient
+		if _, err := client.ListByServerComplete(ctx, *id); err != nil {
+			return fmt.Errorf("retrieving %s: %+v", id, err)
+		}
+
+		return metadata.ResourceData.Set("server_id", firewallrules.NewFlexibleServerID(id.SubscriptionId, id.ResourceGroupName, id.FlexibleServerName).ID())
+	}
+}
+
 func (r FlexibleServerFirewallRulesResource) Read() sdk.ResourceFunc {
 	return sdk.ResourceFunc{
 		Timeout: 5 * time.Minute,
@@ -190,14 +260,48 @@ func (r FlexibleServerFirewallRulesResource) Read() sdk.ResourceFunc {
 
 			rules := make([]Rule, 0)
 			for _, rule := range fwRules.Items {
-				rules = append(rules, Rule{
+				r := Rule{
 					Name:           pointer.From(rule.Name),
 					StartIPAddress: rule.Properties.StartIPAddress,
 					EndIPAddress:   rule.Properties.EndIPAddress,
-				})
+				}
+				if cidr, ok := rangeToCIDR(r.StartIPAddress, r.EndIPAddress); ok {
+					r.CIDR = cidr
+				}
+				rules = append(rules, r)
 			}
 
-			state.Rule = rules
+			// `managed` is absent from state straight after an import, in which case the schema
+			// default (exclusive mode) applies and every rule on the server is read into state.
+			managed := true
+			if v, ok := metadata.ResourceData.GetOkExists("managed"); ok { //nolint:staticcheck
+				managed = v.(bool)
+			}
+			state.Managed = managed
+			if !managed {
+				// Non-exclusive mode: only surface rules this resource owns, so rules created
+				// out-of-band never appear in `rule`/`cidr_rule` and never show up as "will be
+				// removed" against the user's config. Ownership comes from the `tracked_rule_names`
+				// bookkeeping attribute rather than from `rule`/`cidr_rule` themselves, since those
+				// are what Read is about to overwrite.
+				tracked := decodeTrackedRuleNames(metadata.ResourceData)
+				owned := make([]Rule, 0, len(rules))
+				for _, rule := range rules {
+					if _, ok := tracked[rule.Name]; ok {
+						owned = append(owned, rule)
+					}
+				}
+				rules = owned
+			}
+
+			state.Rule, state.CIDRRule = collapseCIDRRules(rules, decodeCIDRRuleBlocks(metadata.ResourceData))
+
+			// Re-persist tracked_rule_names from what's actually still present on the server, so a
+			// rule removed out-of-band (e.g. deleted directly in the Portal) stops being tracked
+			// rather than lingering forever.
+			if err := metadata.ResourceData.Set("tracked_rule_names", trackedRuleNamesFrom(rules)); err != nil {
+				return fmt.Errorf("setting `tracked_rule_names`: %+v", err)
+			}
 
 			return metadata.Encode(&state)
 		},
@@ -224,80 +328,67 @@ func (r FlexibleServerFirewallRulesResource) Update() sdk.ResourceFunc {
 			locks.ByName(id.FlexibleServerName, postgresqlFlexibleServerResourceName)
 			defer locks.UnlockByName(id.FlexibleServerName, postgresqlFlexibleServerResourceName)
 
-			if metadata.ResourceData.HasChange("firewall_rule") {
+			if metadata.ResourceData.HasChange("rule") || metadata.ResourceData.HasChange("cidr_rule") {
 				listFirewallRulesResult, err := client.ListByServerComplete(ctx, *id)
 				if err != nil {
 					return err
 				}
 				currentFirewallRules := listFirewallRulesResult.Items
 
-				firewallRules := make(map[string]firewallrules.FirewallRule)
-				// Build a map of what the firewall rules should look like with the ID as the key
-				for _, rule := range model.Rule {
-					fwRule := firewallrules.FirewallRule{
-						Properties: firewallrules.FirewallRuleProperties{
-							EndIPAddress:   rule.EndIPAddress,
-							StartIPAddress: rule.StartIPAddress,
-						},
-					}
-					fwRuleId := firewallrules.NewFirewallRuleID(subscriptionId, id.ResourceGroupName, id.FlexibleServerName, rule.Name)
-					firewallRules[fwRuleId.ID()] = fwRule
+				rules, err := expandRules(model.Rule, model.CIDRRule)
+				if err != nil {
+					return err
 				}
 
+				serverId := firewallrules.NewFlexibleServerID(subscriptionId, id.ResourceGroupName, id.FlexibleServerName)
+				firewallRules := desiredFirewallRules(rules, serverId)
+
 				rulesToDelete := make([]firewallrules.FirewallRuleId, 0)
 
-				// iterate over the received rules for ID matches for rules to remove.
-				for _, v := range currentFirewallRules {
-					if cId, err := firewallrules.ParseFirewallRuleIDInsensitively(pointer.From(v.Id)); err == nil {
-						if _, ok := firewallRules[cId.ID()]; !ok {
-							rulesToDelete = append(rulesToDelete, *cId)
+				if model.Managed {
+					// iterate over the received rules for ID matches for rules to remove.
+					for _, v := range currentFirewallRules {
+						if cId, err := firewallrules.ParseFirewallRuleIDInsensitively(pointer.From(v.Id)); err == nil {
+							if _, ok := firewallRules[cId.ID()]; !ok {
+								rulesToDelete = append(rulesToDelete, *cId)
+							}
 						}
 					}
-				}
-
-				// Delete removed rules first to avoid potential errors from overlapping ranges or renamed rules
-				semaphore := make(chan struct{}, maxConcurrency)
-				errs := make(chan error)
-				wg := &sync.WaitGroup{}
-				for _, f := range rulesToDelete {
-					wg.Add(1)
-					go batchDelete(ctx, client, f, wg, semaphore, errs)
-				}
-
-				go func() {
-					wg.Wait()
-					close(errs)
-				}()
+				} else {
+					// Non-exclusive mode: only remove rules this resource previously tracked - rules
+					// created out-of-band (e.g. a singleton `azurerm_postgresql_flexible_server_firewall_rule`,
+					// an Azure Portal quick-add, or an "Allow my client IP" auto-rule) are left alone.
+					previouslyTracked := make(map[string]struct{})
+					for name := range decodeTrackedRuleNames(metadata.ResourceData) {
+						fwRuleId := firewallrules.NewFirewallRuleID(subscriptionId, id.ResourceGroupName, id.FlexibleServerName, name)
+						previouslyTracked[fwRuleId.ID()] = struct{}{}
+					}
 
-				for chanErr := range errs {
-					if chanErr != nil {
-						return chanErr
+					for _, v := range currentFirewallRules {
+						if cId, err := firewallrules.ParseFirewallRuleIDInsensitively(pointer.From(v.Id)); err == nil {
+							if _, ok := firewallRules[cId.ID()]; ok {
+								continue
+							}
+							if _, tracked := previouslyTracked[cId.ID()]; tracked {
+								rulesToDelete = append(rulesToDelete, *cId)
+							}
+						}
 					}
 				}
 
-				wg.Wait()
-
-				errs = make(chan error)
+				// Delete removed rules first to avoid potential errors from overlapping ranges or renamed rules
+				if err := deleteRules(ctx, client, rulesToDelete); err != nil {
+					return err
+				}
 
 				// Add / update rules - Rules are governed by their name, so updates and creates do not need to be split here
-				for i, f := range firewallRules {
-					wg.Add(1)
-					fid, _ := firewallrules.ParseFirewallRuleID(i)
-					go batchCreateOrUpdate(ctx, client, *fid, f, wg, semaphore, errs)
+				if err := createOrUpdateRules(ctx, client, firewallRules); err != nil {
+					return err
 				}
 
-				go func() {
-					wg.Wait()
-					close(errs)
-				}()
-
-				for chanErr := range errs {
-					if chanErr != nil {
-						return chanErr
-					}
+				if err := metadata.ResourceData.Set("tracked_rule_names", trackedRuleNamesFrom(rules)); err != nil {
+					return fmt.Errorf("setting `tracked_rule_names`: %+v", err)
 				}
-
-				wg.Wait()
 			}
 
 			return nil
@@ -324,46 +415,134 @@ func (r FlexibleServerFirewallRulesResource) Delete() sdk.ResourceFunc {
 				return err
 			}
 
-			maxRulesAtOnce := make(chan struct{}, maxConcurrency)
-			errs := make(chan error)
-			wg := &sync.WaitGroup{}
+			managed := metadata.ResourceData.Get("managed").(bool)
+			trackedRuleNames := make(map[string]struct{})
+			if !managed {
+				trackedRuleNames = decodeTrackedRuleNames(metadata.ResourceData)
+			}
+
+			ruleIdsToDelete := make([]firewallrules.FirewallRuleId, 0, len(listFirewallRulesResult.Items))
 			for _, v := range listFirewallRulesResult.Items {
 				ruleId, err := firewallrules.ParseFirewallRuleID(pointer.From(v.Id))
 				if err != nil {
 					return fmt.Errorf("deleting Firewall Rules %s: %+v", *id, err)
 				}
-				wg.Add(1)
-				go batchDelete(ctx, client, *ruleId, wg, maxRulesAtOnce, errs)
-			}
-
-			go func() {
-				wg.Wait()
-				close(errs)
-			}()
 
-			for chanErr := range errs {
-				if chanErr != nil {
-					return chanErr
+				if !managed {
+					if _, tracked := trackedRuleNames[ruleId.FirewallRuleName]; !tracked {
+						continue
+					}
 				}
-			}
 
-			wg.Wait()
+				ruleIdsToDelete = append(ruleIdsToDelete, *ruleId)
+			}
 
-			return nil
+			return deleteRules(ctx, client, ruleIdsToDelete)
 		},
 	}
 }
 
-func batchCreateOrUpdate(ctx context.Context, client *firewallrules.FirewallRulesClient, id firewallrules.FirewallRuleId, rule firewallrules.FirewallRule, wg *sync.WaitGroup, semaphore chan struct{}, errs chan error) {
-	defer wg.Done()
-	semaphore <- struct{}{}
-	errs <- client.CreateOrUpdateThenPoll(ctx, id, rule)
-	<-semaphore
+// trackedRuleNamesFrom returns the rule names this resource owns, for persisting into the
+// `tracked_rule_names` bookkeeping attribute.
+func trackedRuleNamesFrom(rules []Rule) []string {
+	names := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+// decodeTrackedRuleNames returns the rule names this resource owns, as persisted in the
+// `tracked_rule_names` bookkeeping attribute by a prior Create/Update/Read. Unlike `rule`/
+// `cidr_rule`, which Read must keep free of anything the user didn't configure, this Computed
+// attribute is not configurable and exists purely so non-exclusive ("managed = false") mode can tell
+// owned rules apart from rules created out-of-band without re-deriving ownership from the same
+// public attribute Read is about to overwrite.
+func decodeTrackedRuleNames(d *pluginsdk.ResourceData) map[string]struct{} {
+	names := make(map[string]struct{})
+	raw, ok := d.GetOk("tracked_rule_names")
+	if !ok {
+		return names
+	}
+	for _, v := range raw.(*pluginsdk.Set).List() {
+		names[v.(string)] = struct{}{}
+	}
+	return names
+}
+
+// decodeCIDRRuleBlocks returns the `cidr_rule` blocks currently in state, used by Read to collapse
+// freshly-listed rules back into the `cidr_rule` they were fanned out from.
+func decodeCIDRRuleBlocks(d *pluginsdk.ResourceData) []CIDRRule {
+	return decodeCIDRRuleList(d.Get("cidr_rule"))
+}
+
+// decodeCIDRRuleList converts a raw `cidr_rule` attribute value (as returned by ResourceData.Get
+// or GetChange) into its typed form.
+func decodeCIDRRuleList(raw interface{}) []CIDRRule {
+	cidrRules := make([]CIDRRule, 0)
+	for _, v := range raw.([]interface{}) {
+		item := v.(map[string]interface{})
+		cidrs := make([]string, 0)
+		for _, c := range item["cidrs"].([]interface{}) {
+			cidrs = append(cidrs, c.(string))
+		}
+		cidrRules = append(cidrRules, CIDRRule{
+			NamePrefix: item["name_prefix"].(string),
+			CIDRs:      cidrs,
+		})
+	}
+	return cidrRules
+}
+
+// desiredRule pairs a Firewall Rule ID with the properties it should have, keyed in a map by
+// that ID's string form so rules can be looked up and diffed by identity.
+type desiredRule struct {
+	id   firewallrules.FirewallRuleId
+	rule firewallrules.FirewallRule
+}
+
+// String identifies the rule in bulkop's partial-failure reporting.
+func (d desiredRule) String() string {
+	return d.id.ID()
+}
+
+// desiredFirewallRules builds the map of what the firewall rules on serverId should look like,
+// keyed by Firewall Rule ID, from the rules expandRules produced for the `rule` and `cidr_rule`
+// blocks.
+func desiredFirewallRules(rules []Rule, serverId firewallrules.FlexibleServerId) map[string]desiredRule {
+	out := make(map[string]desiredRule, len(rules))
+	for _, rule := range rules {
+		fwRuleId := firewallrules.NewFirewallRuleID(serverId.SubscriptionId, serverId.ResourceGroupName, serverId.FlexibleServerName, rule.Name)
+		out[fwRuleId.ID()] = desiredRule{
+			id: fwRuleId,
+			rule: firewallrules.FirewallRule{
+				Properties: firewallrules.FirewallRuleProperties{
+					EndIPAddress:   rule.EndIPAddress,
+					StartIPAddress: rule.StartIPAddress,
+				},
+			},
+		}
+	}
+	return out
+}
+
+// createOrUpdateRules upserts every rule in rules concurrently via bulkop, retrying transient
+// Azure errors and surfacing every naming conflict at once on final failure.
+func createOrUpdateRules(ctx context.Context, client *firewallrules.FirewallRulesClient, rules map[string]desiredRule) error {
+	items := make([]desiredRule, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, rule)
+	}
+
+	return bulkop.Run(ctx, items, bulkop.Config{MaxConcurrency: maxConcurrency, MaxAttempts: maxAttempts}, func(ctx context.Context, item desiredRule) error {
+		return bulkop.ClassifyAzureError(client.CreateOrUpdateThenPoll(ctx, item.id, item.rule))
+	})
 }
 
-func batchDelete(ctx context.Context, client *firewallrules.FirewallRulesClient, id firewallrules.FirewallRuleId, wg *sync.WaitGroup, semaphore chan struct{}, errs chan error) {
-	defer wg.Done()
-	semaphore <- struct{}{}
-	errs <- client.DeleteThenPoll(ctx, id)
-	<-semaphore
+// deleteRules deletes every rule in ids concurrently via bulkop, retrying transient Azure errors
+// and surfacing every failed rule ID at once on final failure.
+func deleteRules(ctx context.Context, client *firewallrules.FirewallRulesClient, ids []firewallrules.FirewallRuleId) error {
+	return bulkop.Run(ctx, ids, bulkop.Config{MaxConcurrency: maxConcurrency, MaxAttempts: maxAttempts}, func(ctx context.Context, id firewallrules.FirewallRuleId) error {
+		return bulkop.ClassifyAzureError(client.DeleteThenPoll(ctx, id))
+	})
 }
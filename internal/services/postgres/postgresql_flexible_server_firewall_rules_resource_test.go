@@ -60,6 +60,75 @@ func TestAccPostgresqlFlexibleServerFirewallRules_update(t *testing.T) {
 	})
 }
 
+func TestAccPostgresqlFlexibleServerFirewallRules_managedFalse(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_postgresql_flexible_server_firewall_rules", "test")
+	r := PostgresqlFlexibleServerFirewallRulesResource{}
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.managedFalse(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+	})
+}
+
+func TestAccPostgresqlFlexibleServerFirewallRules_cidr(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_postgresql_flexible_server_firewall_rules", "test")
+	r := PostgresqlFlexibleServerFirewallRulesResource{}
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.cidr(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+		{
+			Config: r.cidrUpdated(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep(),
+	})
+}
+
+func TestAccPostgresqlFlexibleServerFirewallRules_importNonExclusive(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_postgresql_flexible_server_firewall_rules", "test")
+	r := PostgresqlFlexibleServerFirewallRulesResource{}
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.managedFalse(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				// the out-of-band rule must never be pulled into this resource's state - if it
+				// were, it would show up here and as a permanent "will be removed" diff on refresh.
+				check.That(data.ResourceName).Key("rule.#").HasValue("1"),
+			),
+		},
+		data.ImportStep(),
+		{
+			// the tracked rule's range changes, but the out-of-band rule it depends_on must be
+			// left untouched by the update.
+			Config: r.managedFalseUpdated(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("rule.#").HasValue("1"),
+			),
+		},
+		data.ImportStep(),
+		{
+			// a bare refresh (no config change) must not re-introduce the out-of-band rule either.
+			RefreshState: true,
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("rule.#").HasValue("1"),
+			),
+		},
+	})
+}
+
 func (PostgresqlFlexibleServerFirewallRulesResource) Exists(ctx context.Context, clients *clients.Client, state *pluginsdk.InstanceState) (*bool, error) {
 	id, err := firewallrules.ParseFlexibleServerID(state.ID)
 	if err != nil {
@@ -104,6 +173,98 @@ resource "azurerm_postgresql_flexible_server_firewall_rules" "test" {
 `, PostgresqlFlexibleServerResource{}.basic(data), data.RandomInteger)
 }
 
+func (PostgresqlFlexibleServerFirewallRulesResource) managedFalse(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_postgresql_flexible_server_firewall_rule" "out_of_band" {
+  name             = "acctest-FSFR-oob-%[2]d"
+  server_id        = azurerm_postgresql_flexible_server.test.id
+  start_ip_address = "130.0.0.0"
+  end_ip_address   = "130.0.0.0"
+}
+
+resource "azurerm_postgresql_flexible_server_firewall_rules" "test" {
+  server_id = azurerm_postgresql_flexible_server.test.id
+  managed   = false
+  rule {
+    name             = "acctest-FSFR-%[2]d"
+    start_ip_address = "120.0.0.0"
+    end_ip_address   = "120.0.0.0"
+  }
+
+  depends_on = [azurerm_postgresql_flexible_server_firewall_rule.out_of_band]
+}
+`, PostgresqlFlexibleServerResource{}.basic(data), data.RandomInteger)
+}
+
+func (PostgresqlFlexibleServerFirewallRulesResource) cidr(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_postgresql_flexible_server_firewall_rules" "test" {
+  server_id = azurerm_postgresql_flexible_server.test.id
+  rule {
+    name = "acctest-FSFR-%[2]d"
+    cidr = "120.0.0.0/24"
+  }
+  cidr_rule {
+    name_prefix = "acctest-FSFR-allowlist-%[2]d"
+    cidrs = [
+      "121.0.0.0/24",
+      "122.0.0.0/24",
+    ]
+  }
+}
+`, PostgresqlFlexibleServerResource{}.basic(data), data.RandomInteger)
+}
+
+func (PostgresqlFlexibleServerFirewallRulesResource) cidrUpdated(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_postgresql_flexible_server_firewall_rules" "test" {
+  server_id = azurerm_postgresql_flexible_server.test.id
+  rule {
+    name = "acctest-FSFR-%[2]d"
+    cidr = "120.0.0.0/24"
+  }
+  cidr_rule {
+    name_prefix = "acctest-FSFR-allowlist-%[2]d"
+    cidrs = [
+      "121.0.0.0/24",
+      "123.0.0.0/24",
+    ]
+  }
+}
+`, PostgresqlFlexibleServerResource{}.basic(data), data.RandomInteger)
+}
+
+func (PostgresqlFlexibleServerFirewallRulesResource) managedFalseUpdated(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_postgresql_flexible_server_firewall_rule" "out_of_band" {
+  name             = "acctest-FSFR-oob-%[2]d"
+  server_id        = azurerm_postgresql_flexible_server.test.id
+  start_ip_address = "130.0.0.0"
+  end_ip_address   = "130.0.0.0"
+}
+
+resource "azurerm_postgresql_flexible_server_firewall_rules" "test" {
+  server_id = azurerm_postgresql_flexible_server.test.id
+  managed   = false
+  rule {
+    name             = "acctest-FSFR-%[2]d"
+    start_ip_address = "120.0.0.1"
+    end_ip_address   = "120.0.0.254"
+  }
+
+  depends_on = [azurerm_postgresql_flexible_server_firewall_rule.out_of_band]
+}
+`, PostgresqlFlexibleServerResource{}.basic(data), data.RandomInteger)
+}
+
 func (r PostgresqlFlexibleServerFirewallRulesResource) update(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %s